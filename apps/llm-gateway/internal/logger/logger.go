@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 
 	"github.com/joho/godotenv"
@@ -10,6 +11,41 @@ import (
 
 var Log *zap.Logger
 
+type ctxKey int
+
+const (
+	loggerCtxKey ctxKey = iota
+	requestIDCtxKey
+)
+
+// FromContext returns the request-scoped logger attached by NewContext, or
+// the package-global Log if ctx carries none (e.g. background jobs and
+// tests that never went through the gateway's HTTP middleware).
+func FromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(loggerCtxKey).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return Log
+}
+
+// NewContext returns a context carrying a logger scoped to one request:
+// Log with requestID and any extra correlation fields (session_id, user_id,
+// ...) already attached. The gateway's HTTP middleware calls this once per
+// inbound request; RequestID recovers the bare ID from the same context for
+// non-logging uses such as usage auditing.
+func NewContext(ctx context.Context, requestID string, fields ...zap.Field) context.Context {
+	scoped := Log.With(append([]zap.Field{zap.String("request_id", requestID)}, fields...)...)
+	ctx = context.WithValue(ctx, loggerCtxKey, scoped)
+	return context.WithValue(ctx, requestIDCtxKey, requestID)
+}
+
+// RequestID returns the request ID attached by NewContext, or "" if ctx
+// carries none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey).(string)
+	return id
+}
+
 func Init() error {
 	level := zap.NewAtomicLevel()
 	_ = godotenv.Load(".env.dev")
@@ -0,0 +1,63 @@
+// Package metrics holds the gateway's cross-cutting observability surface:
+// the Prometheus registry for vLLM client metrics and the OpenTelemetry
+// tracer used to wrap upstream HTTP calls.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+)
+
+// Tracer is the gateway's tracer for spans wrapping upstream provider
+// calls. Using a package-level tracer (rather than threading one through
+// every constructor) matches how logger.Log is already used as a
+// process-wide singleton.
+var Tracer = otel.Tracer("github.com/yungtweek/talkie/apps/llm-gateway")
+
+var (
+	// VLLMRequestsTotal counts every Chat/ChatStream attempt against vLLM,
+	// labeled by model and outcome.
+	VLLMRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vllm_requests_total",
+		Help: "Total number of vLLM chat completion requests.",
+	}, []string{"model", "status"})
+
+	// VLLMRequestDuration measures end-to-end call latency, labeled by
+	// whether the call was streaming.
+	VLLMRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vllm_request_duration_seconds",
+		Help:    "Latency of vLLM chat completion requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"model", "stream"})
+
+	// VLLMTokensTotal counts prompt and completion tokens processed.
+	VLLMTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vllm_tokens_total",
+		Help: "Total number of tokens processed by vLLM, by kind.",
+	}, []string{"model", "kind"})
+
+	// VLLMStreamTTFT measures the time from request send to the first
+	// non-empty output_text.delta chunk.
+	VLLMStreamTTFT = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vllm_stream_ttft_seconds",
+		Help:    "Time to first token for a vLLM streamed chat completion.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"model"})
+
+	// VLLMStreamInterTokenLatency measures the gap between consecutive
+	// non-empty output_text.delta chunks.
+	VLLMStreamInterTokenLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vllm_stream_inter_token_latency_seconds",
+		Help:    "Gap between consecutive tokens in a vLLM streamed chat completion.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"model"})
+)
+
+// Handler returns the HTTP handler to mount at /metrics on the gateway's mux.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+)
+
+// BuildRouter constructs a Router and its providers from a Config. vLLM
+// providers are backed by a fresh vllm.Client per entry; the others speak
+// directly to their upstream API.
+func BuildRouter(cfg *Config, requestTimeout time.Duration) (*Router, error) {
+	entries := make([]Entry, 0, len(cfg.Providers))
+
+	for _, pc := range cfg.Providers {
+		provider, err := newProvider(pc, requestTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("router: provider %q: %w", pc.Name, err)
+		}
+		entries = append(entries, Entry{Provider: provider, Config: pc})
+	}
+
+	health := NewHealthTracker(cfg.FailureThreshold, cfg.Cooldown)
+	return NewRouter(entries, health), nil
+}
+
+func newProvider(cfg ProviderConfig, timeout time.Duration) (Provider, error) {
+	switch cfg.Kind {
+	case "vllm":
+		return NewVLLMProvider(cfg.Name, vllm.NewClient(cfg.BaseURL, int(timeout.Milliseconds()))), nil
+	case "openai":
+		return NewOpenAIProvider(cfg, timeout), nil
+	case "anthropic":
+		return NewAnthropicProvider(cfg, timeout), nil
+	case "cohere":
+		return NewCohereProvider(cfg, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown provider kind %q", cfg.Kind)
+	}
+}
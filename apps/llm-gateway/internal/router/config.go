@@ -0,0 +1,64 @@
+package router
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderConfig describes one named backend in the fallback list.
+type ProviderConfig struct {
+	Name     string `yaml:"name"`
+	Kind     string `yaml:"kind"` // "vllm", "openai", "anthropic", "cohere"
+	BaseURL  string `yaml:"base_url"`
+	APIKey   string `yaml:"api_key"`
+	Model    string `yaml:"model"`
+	Weight   int    `yaml:"weight"`   // relative odds of being tried first among same-Priority providers; <= 0 behaves as 1
+	Priority int    `yaml:"priority"` // lower priority value is tried first
+}
+
+// Config is the YAML-defined set of providers the router fans out to, plus
+// the health tracking knobs shared across all of them.
+type Config struct {
+	Providers []ProviderConfig `yaml:"providers"`
+
+	// FailureThreshold is the number of consecutive failures that marks a
+	// provider unhealthy.
+	FailureThreshold int `yaml:"failure_threshold"`
+
+	// Cooldown is how long an unhealthy provider is skipped before the
+	// router gives it another chance.
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+// LoadConfig reads and validates a router config from a YAML file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("router: failed to read config %q: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("router: failed to parse config %q: %w", path, err)
+	}
+
+	if len(cfg.Providers) == 0 {
+		return nil, fmt.Errorf("router: config %q declares no providers", path)
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 3
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+
+	sort.SliceStable(cfg.Providers, func(i, j int) bool {
+		return cfg.Providers[i].Priority < cfg.Providers[j].Priority
+	})
+
+	return &cfg, nil
+}
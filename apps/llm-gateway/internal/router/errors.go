@@ -0,0 +1,25 @@
+package router
+
+import "errors"
+
+// statusError lets a Provider surface the HTTP status it received without
+// the router needing to know about that provider's wire format. Providers
+// should wrap it with fmt.Errorf("%w: ...", &statusError{...}) style errors
+// so errors.As below can still unwrap it.
+type statusError struct {
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return "upstream returned non-2xx status"
+}
+
+// newStatusError is the constructor providers use when building the error
+// they return from Chat/ChatStream for a non-2xx response.
+func newStatusError(code int) error {
+	return &statusError{StatusCode: code}
+}
+
+func asStatusError(err error, target **statusError) bool {
+	return errors.As(err, target)
+}
@@ -0,0 +1,131 @@
+package router
+
+import (
+	"sync"
+	"time"
+)
+
+// providerHealth is the rolling state the HealthTracker keeps per provider.
+type providerHealth struct {
+	mu sync.Mutex
+
+	consecutiveFailures int
+	unhealthyUntil      time.Time
+
+	requests     int64
+	successes    int64
+	authErrors   int64
+	serverErrors int64
+	totalLatency time.Duration
+}
+
+// HealthTracker records per-provider success/latency/error counters and
+// decides when a provider should be skipped in favor of the next one in
+// the fallback list.
+type HealthTracker struct {
+	failureThreshold int
+	cooldown         time.Duration
+
+	mu    sync.Mutex
+	state map[string]*providerHealth
+}
+
+// NewHealthTracker builds a tracker that marks a provider unhealthy after
+// failureThreshold consecutive failures (or a single auth error) and keeps
+// it out of rotation for cooldown before retrying it.
+func NewHealthTracker(failureThreshold int, cooldown time.Duration) *HealthTracker {
+	return &HealthTracker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		state:            make(map[string]*providerHealth),
+	}
+}
+
+func (h *HealthTracker) entry(name string) *providerHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p, ok := h.state[name]
+	if !ok {
+		p = &providerHealth{}
+		h.state[name] = p
+	}
+	return p
+}
+
+// RecordSuccess resets the consecutive-failure count and adds the latency
+// to the provider's cumulative totals (kept since the tracker was built,
+// never decayed or windowed).
+func (h *HealthTracker) RecordSuccess(name string, latency time.Duration) {
+	p := h.entry(name)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests++
+	p.successes++
+	p.consecutiveFailures = 0
+	p.totalLatency += latency
+}
+
+// RecordFailure registers a failed call. isAuthError and isServerError let
+// the caller distinguish 401/403 (treated as an immediate outage) from a
+// 5xx or transport error (counted toward the consecutive-failure window).
+func (h *HealthTracker) RecordFailure(name string, isAuthError, isServerError bool) {
+	p := h.entry(name)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests++
+	if isAuthError {
+		p.authErrors++
+	}
+	if isServerError {
+		p.serverErrors++
+	}
+	p.consecutiveFailures++
+
+	if isAuthError || p.consecutiveFailures >= h.failureThreshold {
+		p.unhealthyUntil = time.Now().Add(h.cooldown)
+	}
+}
+
+// IsHealthy reports whether a provider is currently eligible for traffic.
+// A provider with no recorded history is considered healthy.
+func (h *HealthTracker) IsHealthy(name string) bool {
+	p := h.entry(name)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return time.Now().After(p.unhealthyUntil)
+}
+
+// Snapshot returns a point-in-time copy of a provider's counters, mainly
+// for logging and debug endpoints.
+type Snapshot struct {
+	Requests     int64
+	Successes    int64
+	AuthErrors   int64
+	ServerErrors int64
+	AvgLatency   time.Duration
+	Unhealthy    bool
+}
+
+func (h *HealthTracker) Snapshot(name string) Snapshot {
+	p := h.entry(name)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var avg time.Duration
+	if p.successes > 0 {
+		avg = p.totalLatency / time.Duration(p.successes)
+	}
+
+	return Snapshot{
+		Requests:     p.requests,
+		Successes:    p.successes,
+		AuthErrors:   p.authErrors,
+		ServerErrors: p.serverErrors,
+		AvgLatency:   avg,
+		Unhealthy:    time.Now().Before(p.unhealthyUntil),
+	}
+}
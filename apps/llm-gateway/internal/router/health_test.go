@@ -0,0 +1,84 @@
+package router
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthTracker_NoHistoryIsHealthy(t *testing.T) {
+	h := NewHealthTracker(3, time.Minute)
+
+	if !h.IsHealthy("p") {
+		t.Fatal("a provider with no recorded history should be healthy")
+	}
+}
+
+func TestHealthTracker_UnhealthyAfterConsecutiveFailures(t *testing.T) {
+	h := NewHealthTracker(2, time.Minute)
+
+	h.RecordFailure("p", false, false)
+	if !h.IsHealthy("p") {
+		t.Fatal("one failure below threshold should still be healthy")
+	}
+
+	h.RecordFailure("p", false, false)
+	if h.IsHealthy("p") {
+		t.Fatal("reaching failureThreshold consecutive failures should mark the provider unhealthy")
+	}
+}
+
+func TestHealthTracker_AuthErrorIsImmediatelyUnhealthy(t *testing.T) {
+	h := NewHealthTracker(5, time.Minute)
+
+	h.RecordFailure("p", true, false)
+	if h.IsHealthy("p") {
+		t.Fatal("a single auth error should mark the provider unhealthy regardless of threshold")
+	}
+}
+
+func TestHealthTracker_SuccessResetsConsecutiveFailures(t *testing.T) {
+	h := NewHealthTracker(2, time.Minute)
+
+	h.RecordFailure("p", false, false)
+	h.RecordSuccess("p", time.Millisecond)
+	h.RecordFailure("p", false, false)
+	if !h.IsHealthy("p") {
+		t.Fatal("a success between failures should reset the consecutive-failure count")
+	}
+}
+
+func TestHealthTracker_RecoversAfterCooldown(t *testing.T) {
+	h := NewHealthTracker(1, 10*time.Millisecond)
+
+	h.RecordFailure("p", false, false)
+	if h.IsHealthy("p") {
+		t.Fatal("expected unhealthy immediately after crossing the threshold")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !h.IsHealthy("p") {
+		t.Fatal("expected healthy again once the cooldown window has elapsed")
+	}
+}
+
+func TestHealthTracker_SnapshotCounters(t *testing.T) {
+	h := NewHealthTracker(3, time.Minute)
+
+	h.RecordSuccess("p", 10*time.Millisecond)
+	h.RecordSuccess("p", 20*time.Millisecond)
+	h.RecordFailure("p", false, true)
+
+	snap := h.Snapshot("p")
+	if snap.Requests != 3 {
+		t.Fatalf("Requests = %d, want 3", snap.Requests)
+	}
+	if snap.Successes != 2 {
+		t.Fatalf("Successes = %d, want 2", snap.Successes)
+	}
+	if snap.ServerErrors != 1 {
+		t.Fatalf("ServerErrors = %d, want 1", snap.ServerErrors)
+	}
+	if snap.AvgLatency != 15*time.Millisecond {
+		t.Fatalf("AvgLatency = %v, want %v", snap.AvgLatency, 15*time.Millisecond)
+	}
+}
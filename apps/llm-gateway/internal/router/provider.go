@@ -0,0 +1,33 @@
+// Package router fans a single ChatCompletionRequest out to one of several
+// named LLM backends (vLLM, OpenAI, Anthropic, Cohere, ...), tracking the
+// health of each and falling back to the next candidate when one is
+// unhealthy or fails mid-request.
+package router
+
+import (
+	"context"
+
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+)
+
+// Provider is the common interface every backend adapter implements. It
+// mirrors vllm.Client's shape so the vLLM provider is a thin wrapper rather
+// than a rewrite.
+type Provider interface {
+	// Name identifies the provider for logging, metrics, and config lookup.
+	Name() string
+	Chat(ctx context.Context, req vllm.ChatCompletionRequest) (*vllm.ChatCompletionResponse, error)
+	ChatStream(ctx context.Context, req vllm.ChatCompletionRequest, onChunk vllm.StreamHandler) error
+}
+
+// StreamCapable is an optional interface a Provider implements to declare
+// that it doesn't support ChatStream. A Provider that doesn't implement
+// this interface is assumed to support streaming. Router.ChatStream checks
+// this before dispatching so a stream-incapable provider is skipped
+// outright rather than having its ChatStream stub's error recorded against
+// the HealthTracker, which Chat and ChatStream share keyed by provider
+// name - otherwise stream traffic against such a provider would eventually
+// mark it unhealthy and degrade its ordinary Chat traffic too.
+type StreamCapable interface {
+	SupportsStream() bool
+}
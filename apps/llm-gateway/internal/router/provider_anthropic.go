@@ -0,0 +1,139 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+)
+
+// anthropicRequest is Anthropic's Messages API request shape, which differs
+// from the OpenAI-style ChatCompletionRequest enough (no "system" role
+// inline, different envelope) to need its own translation.
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	ID         string `json:"id"`
+	Model      string `json:"model"`
+	StopReason string `json:"stop_reason"`
+	Content    []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// anthropicProvider talks to Anthropic's Messages API.
+type anthropicProvider struct {
+	name  string
+	model string
+	http  *resty.Client
+}
+
+// NewAnthropicProvider builds a Provider backed by the Anthropic Messages
+// API.
+func NewAnthropicProvider(cfg ProviderConfig, timeout time.Duration) Provider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("x-api-key", cfg.APIKey).
+		SetHeader("anthropic-version", "2023-06-01").
+		SetTimeout(timeout)
+
+	return &anthropicProvider{name: cfg.Name, model: cfg.Model, http: client}
+}
+
+func (p *anthropicProvider) Name() string { return p.name }
+
+func toAnthropicRequest(req vllm.ChatCompletionRequest, model string) anthropicRequest {
+	var system string
+	messages := make([]anthropicMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			system = m.Content
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 1024
+	}
+
+	return anthropicRequest{Model: model, System: system, Messages: messages, MaxTokens: maxTokens}
+}
+
+func (p *anthropicProvider) Chat(ctx context.Context, req vllm.ChatCompletionRequest) (*vllm.ChatCompletionResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = p.model
+	}
+	body := toAnthropicRequest(req, model)
+
+	var raw anthropicResponse
+	r, err := p.http.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&raw).
+		Post("/v1/messages")
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: request failed: %w", err)
+	}
+	if status := r.StatusCode(); status < 200 || status >= 300 {
+		return nil, fmt.Errorf("anthropic: non-2xx status %d: %w", status, newStatusError(status))
+	}
+
+	var text string
+	if len(raw.Content) > 0 {
+		text = raw.Content[0].Text
+	}
+
+	resp := &vllm.ChatCompletionResponse{
+		ID:    raw.ID,
+		Model: raw.Model,
+	}
+	resp.Choices = []struct {
+		Index        int              `json:"index"`
+		Message      vllm.ChatMessage `json:"message"`
+		FinishReason string           `json:"finish_reason"`
+	}{
+		{Index: 0, Message: vllm.ChatMessage{Role: "assistant", Content: text}, FinishReason: raw.StopReason},
+	}
+	resp.Usage.PromptTokens = raw.Usage.InputTokens
+	resp.Usage.CompletionTokens = raw.Usage.OutputTokens
+	resp.Usage.TotalTokens = raw.Usage.InputTokens + raw.Usage.OutputTokens
+
+	return resp, nil
+}
+
+// ChatStream is not yet implemented for Anthropic; SupportsStream reports
+// false so the router skips this provider for streaming requests rather
+// than dispatching into this stub.
+func (p *anthropicProvider) ChatStream(ctx context.Context, req vllm.ChatCompletionRequest, onChunk vllm.StreamHandler) error {
+	return fmt.Errorf("anthropic: streaming not supported yet")
+}
+
+// SupportsStream reports false until SSE support for the Messages API
+// lands. See StreamCapable.
+func (p *anthropicProvider) SupportsStream() bool { return false }
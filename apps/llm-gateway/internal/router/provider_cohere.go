@@ -0,0 +1,124 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+)
+
+// cohereRequest is Cohere's Chat API request shape: a single "message" plus
+// a "chat_history" list, rather than OpenAI's flat messages array.
+type cohereRequest struct {
+	Model       string          `json:"model"`
+	Message     string          `json:"message"`
+	ChatHistory []cohereHistory `json:"chat_history,omitempty"`
+	Preamble    string          `json:"preamble,omitempty"`
+}
+
+type cohereHistory struct {
+	Role    string `json:"role"`
+	Message string `json:"message"`
+}
+
+type cohereResponse struct {
+	Text         string `json:"text"`
+	FinishReason string `json:"finish_reason"`
+	Meta         struct {
+		Tokens struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"tokens"`
+	} `json:"meta"`
+}
+
+// cohereProvider talks to Cohere's Chat API.
+type cohereProvider struct {
+	name  string
+	model string
+	http  *resty.Client
+}
+
+// NewCohereProvider builds a Provider backed by Cohere's Chat API.
+func NewCohereProvider(cfg ProviderConfig, timeout time.Duration) Provider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.cohere.ai"
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Authorization", "Bearer "+cfg.APIKey).
+		SetTimeout(timeout)
+
+	return &cohereProvider{name: cfg.Name, model: cfg.Model, http: client}
+}
+
+func (p *cohereProvider) Name() string { return p.name }
+
+func toCohereRequest(req vllm.ChatCompletionRequest, model string) cohereRequest {
+	var preamble string
+	history := make([]cohereHistory, 0, len(req.Messages))
+	var message string
+
+	for i, m := range req.Messages {
+		switch {
+		case m.Role == "system":
+			preamble = m.Content
+		case i == len(req.Messages)-1:
+			message = m.Content
+		default:
+			history = append(history, cohereHistory{Role: m.Role, Message: m.Content})
+		}
+	}
+
+	return cohereRequest{Model: model, Message: message, ChatHistory: history, Preamble: preamble}
+}
+
+func (p *cohereProvider) Chat(ctx context.Context, req vllm.ChatCompletionRequest) (*vllm.ChatCompletionResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = p.model
+	}
+	body := toCohereRequest(req, model)
+
+	var raw cohereResponse
+	r, err := p.http.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&raw).
+		Post("/v1/chat")
+	if err != nil {
+		return nil, fmt.Errorf("cohere: request failed: %w", err)
+	}
+	if status := r.StatusCode(); status < 200 || status >= 300 {
+		return nil, fmt.Errorf("cohere: non-2xx status %d: %w", status, newStatusError(status))
+	}
+
+	resp := &vllm.ChatCompletionResponse{Model: model}
+	resp.Choices = []struct {
+		Index        int              `json:"index"`
+		Message      vllm.ChatMessage `json:"message"`
+		FinishReason string           `json:"finish_reason"`
+	}{
+		{Index: 0, Message: vllm.ChatMessage{Role: "assistant", Content: raw.Text}, FinishReason: raw.FinishReason},
+	}
+	resp.Usage.PromptTokens = raw.Meta.Tokens.InputTokens
+	resp.Usage.CompletionTokens = raw.Meta.Tokens.OutputTokens
+	resp.Usage.TotalTokens = raw.Meta.Tokens.InputTokens + raw.Meta.Tokens.OutputTokens
+
+	return resp, nil
+}
+
+// ChatStream is not yet implemented for Cohere; SupportsStream reports
+// false so the router skips this provider for streaming requests rather
+// than dispatching into this stub.
+func (p *cohereProvider) ChatStream(ctx context.Context, req vllm.ChatCompletionRequest, onChunk vllm.StreamHandler) error {
+	return fmt.Errorf("cohere: streaming not supported yet")
+}
+
+// SupportsStream reports false for now. See StreamCapable.
+func (p *cohereProvider) SupportsStream() bool { return false }
@@ -0,0 +1,137 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/logger"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/sse"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+	"go.uber.org/zap"
+)
+
+// openAIProvider talks to the real OpenAI API, which vLLM's own wire format
+// already mirrors, so requests/responses are passed through unchanged.
+type openAIProvider struct {
+	name  string
+	model string
+	http  *resty.Client
+}
+
+// NewOpenAIProvider builds a Provider backed by OpenAI's chat completions
+// endpoint.
+func NewOpenAIProvider(cfg ProviderConfig, timeout time.Duration) Provider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com"
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Authorization", "Bearer "+cfg.APIKey).
+		SetTimeout(timeout)
+
+	return &openAIProvider{name: cfg.Name, model: cfg.Model, http: client}
+}
+
+func (p *openAIProvider) Name() string { return p.name }
+
+func (p *openAIProvider) Chat(ctx context.Context, req vllm.ChatCompletionRequest) (*vllm.ChatCompletionResponse, error) {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+	req.Stream = false
+
+	var resp vllm.ChatCompletionResponse
+	r, err := p.http.R().
+		SetContext(ctx).
+		SetBody(req).
+		SetResult(&resp).
+		Post("/v1/chat/completions")
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+	if status := r.StatusCode(); status < 200 || status >= 300 {
+		return nil, fmt.Errorf("openai: non-2xx status %d: %w", status, newStatusError(status))
+	}
+
+	return &resp, nil
+}
+
+func (p *openAIProvider) ChatStream(ctx context.Context, req vllm.ChatCompletionRequest, onChunk vllm.StreamHandler) error {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+	req.Stream = true
+
+	r, err := p.http.R().
+		SetContext(ctx).
+		SetBody(req).
+		SetDoNotParseResponse(true).
+		Post("/v1/chat/completions")
+	if err != nil {
+		return fmt.Errorf("openai: stream request failed: %w", err)
+	}
+	defer func(body io.ReadCloser) {
+		if cerr := body.Close(); cerr != nil {
+			logger.Log.Warn("openai: failed to close stream body", zap.Error(cerr))
+		}
+	}(r.RawBody())
+
+	if status := r.StatusCode(); status < 200 || status >= 300 {
+		return fmt.Errorf("openai: stream non-2xx status %d: %w", status, newStatusError(status))
+	}
+
+	reader := sse.NewReader(r.RawBody())
+	var sawDone bool
+	for {
+		event, err := reader.Next()
+		if err != nil {
+			if err == io.EOF {
+				if !sawDone {
+					// The body closed cleanly but without a terminal
+					// [DONE] marker, so the generation is incomplete
+					// rather than finished; don't report this as success.
+					return fmt.Errorf("openai: stream closed before [DONE]")
+				}
+				return nil
+			}
+			return fmt.Errorf("openai: SSE read error: %w", err)
+		}
+
+		if event.IsComment() {
+			// Keep-alive; nothing to dispatch.
+			continue
+		}
+
+		payload := event.Data
+		if payload == "[DONE]" {
+			sawDone = true
+			return nil
+		}
+
+		var raw vllm.OpenAIChatCompletionChunk
+		if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+			return fmt.Errorf("openai: failed to unmarshal stream chunk: %w", err)
+		}
+		if len(raw.Choices) == 0 {
+			continue
+		}
+
+		choice := raw.Choices[0]
+		chunk := vllm.ChatCompletionStreamChunk{
+			Type:         "output_text.delta",
+			Text:         choice.Delta.Content,
+			FinishReason: choice.FinishReason,
+			Index:        choice.Index,
+		}
+		if err := onChunk(chunk); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"context"
+
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+)
+
+// vllmProvider adapts the existing vllm.Client to the Provider interface.
+// vllm.Client doesn't currently surface the HTTP status code on error, so
+// failures from it are only ever counted as consecutive failures, never
+// classified as auth errors.
+type vllmProvider struct {
+	name   string
+	client *vllm.Client
+}
+
+// NewVLLMProvider wraps an existing vLLM client for use in a Router.
+func NewVLLMProvider(name string, client *vllm.Client) Provider {
+	return &vllmProvider{name: name, client: client}
+}
+
+func (p *vllmProvider) Name() string { return p.name }
+
+func (p *vllmProvider) Chat(ctx context.Context, req vllm.ChatCompletionRequest) (*vllm.ChatCompletionResponse, error) {
+	return p.client.Chat(ctx, req)
+}
+
+func (p *vllmProvider) ChatStream(ctx context.Context, req vllm.ChatCompletionRequest, onChunk vllm.StreamHandler) error {
+	return p.client.ChatStream(ctx, req, onChunk)
+}
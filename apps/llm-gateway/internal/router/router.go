@@ -0,0 +1,221 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/logger"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+	"go.uber.org/zap"
+)
+
+// Entry pairs a Provider with the config it was built from, so the router
+// can order candidates by priority without a second lookup.
+type Entry struct {
+	Provider Provider
+	Config   ProviderConfig
+}
+
+// Router dispatches a ChatCompletionRequest to the first healthy provider
+// in priority order, falling back to the next one on failure.
+type Router struct {
+	entries []Entry
+	health  *HealthTracker
+}
+
+// NewRouter builds a Router over entries already sorted by priority (as
+// Config.Providers is after LoadConfig).
+func NewRouter(entries []Entry, health *HealthTracker) *Router {
+	return &Router{entries: entries, health: health}
+}
+
+// candidates returns the fallback order for this call: healthy providers
+// first (in priority order), then unhealthy ones as a last resort so a
+// request doesn't fail outright just because every provider is cooling down.
+// Within a tied priority, Config.Weight decides the try order via weighted
+// random sampling rather than config file order.
+func (r *Router) candidates() []Entry {
+	healthy := make([]Entry, 0, len(r.entries))
+	unhealthy := make([]Entry, 0)
+
+	for _, e := range r.entries {
+		if r.health.IsHealthy(e.Provider.Name()) {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+
+	return append(weightedPriorityOrder(healthy), weightedPriorityOrder(unhealthy)...)
+}
+
+// weightedPriorityOrder takes entries already sorted by Config.Priority and
+// reorders each same-priority run by weighted random sampling without
+// replacement, so a higher Config.Weight makes a provider more likely to be
+// tried first among its priority peers without changing priority tiers.
+func weightedPriorityOrder(entries []Entry) []Entry {
+	ordered := make([]Entry, 0, len(entries))
+	for i := 0; i < len(entries); {
+		j := i + 1
+		for j < len(entries) && entries[j].Config.Priority == entries[i].Config.Priority {
+			j++
+		}
+		ordered = append(ordered, weightedShuffle(entries[i:j])...)
+		i = j
+	}
+	return ordered
+}
+
+// weightedShuffle orders entries by repeatedly drawing (without
+// replacement) from the remaining entries with probability proportional to
+// weight. A non-positive Config.Weight is treated as 1 so an unset weight
+// doesn't silently make a provider unreachable.
+func weightedShuffle(entries []Entry) []Entry {
+	if len(entries) <= 1 {
+		out := make([]Entry, len(entries))
+		copy(out, entries)
+		return out
+	}
+
+	remaining := make([]Entry, len(entries))
+	copy(remaining, entries)
+	ordered := make([]Entry, 0, len(entries))
+
+	for len(remaining) > 0 {
+		total := 0
+		for _, e := range remaining {
+			total += providerWeight(e.Config)
+		}
+
+		pick := rand.Intn(total)
+		idx, cum := 0, 0
+		for i, e := range remaining {
+			cum += providerWeight(e.Config)
+			if pick < cum {
+				idx = i
+				break
+			}
+		}
+
+		ordered = append(ordered, remaining[idx])
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+	}
+
+	return ordered
+}
+
+func providerWeight(cfg ProviderConfig) int {
+	if cfg.Weight <= 0 {
+		return 1
+	}
+	return cfg.Weight
+}
+
+func isAuthStatus(err error) bool {
+	var se *statusError
+	if ok := asStatusError(err, &se); ok {
+		return se.StatusCode == http.StatusUnauthorized || se.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+func isServerStatus(err error) bool {
+	var se *statusError
+	if ok := asStatusError(err, &se); ok {
+		return se.StatusCode >= 500
+	}
+	return false
+}
+
+// Chat tries each provider in fallback order until one succeeds.
+func (r *Router) Chat(ctx context.Context, req vllm.ChatCompletionRequest) (*vllm.ChatCompletionResponse, error) {
+	var lastErr error
+	log := logger.FromContext(ctx)
+
+	for _, e := range r.candidates() {
+		start := time.Now()
+		resp, err := e.Provider.Chat(ctx, req)
+		if err == nil {
+			r.health.RecordSuccess(e.Provider.Name(), time.Since(start))
+			return resp, nil
+		}
+
+		r.health.RecordFailure(e.Provider.Name(), isAuthStatus(err), isServerStatus(err))
+		log.Warn("router: provider failed, trying next",
+			zap.String("provider", e.Provider.Name()),
+			zap.Error(err),
+		)
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("router: all providers failed: %w", lastErr)
+}
+
+// ChatStream streams from the first healthy provider. If the provider
+// errors before emitting a single chunk, its context is cancelled and the
+// request transparently retries on the next healthy provider. Once a chunk
+// has reached onChunk, a mid-stream failure is terminal: the caller
+// receives an "error" finish-reason chunk carrying whatever usage was
+// accumulated rather than silently resuming on another backend.
+func (r *Router) ChatStream(ctx context.Context, req vllm.ChatCompletionRequest, onChunk vllm.StreamHandler) error {
+	var lastErr error
+	log := logger.FromContext(ctx)
+
+	for _, e := range r.candidates() {
+		if sc, ok := e.Provider.(StreamCapable); ok && !sc.SupportsStream() {
+			log.Debug("router: provider does not support streaming, trying next",
+				zap.String("provider", e.Provider.Name()),
+			)
+			lastErr = fmt.Errorf("router: provider %q does not support streaming", e.Provider.Name())
+			continue
+		}
+
+		var gotChunk bool
+		var lastChunk vllm.ChatCompletionStreamChunk
+
+		attemptCtx, cancel := context.WithCancel(ctx)
+		start := time.Now()
+
+		err := e.Provider.ChatStream(attemptCtx, req, func(chunk vllm.ChatCompletionStreamChunk) error {
+			gotChunk = true
+			lastChunk = chunk
+			return onChunk(chunk)
+		})
+		cancel()
+
+		if err == nil {
+			r.health.RecordSuccess(e.Provider.Name(), time.Since(start))
+			return nil
+		}
+
+		r.health.RecordFailure(e.Provider.Name(), isAuthStatus(err), isServerStatus(err))
+
+		if !gotChunk {
+			// Nothing reached the caller yet, so it's safe to retry on the
+			// next provider without it noticing.
+			log.Warn("router: provider failed before first token, retrying",
+				zap.String("provider", e.Provider.Name()),
+				zap.Error(err),
+			)
+			lastErr = err
+			continue
+		}
+
+		log.Warn("router: provider failed mid-stream, surfacing terminal error chunk",
+			zap.String("provider", e.Provider.Name()),
+			zap.Error(err),
+		)
+		errChunk := lastChunk
+		errChunk.Type = "output_text.done"
+		errChunk.FinishReason = "error"
+		if cbErr := onChunk(errChunk); cbErr != nil {
+			return cbErr
+		}
+		return fmt.Errorf("router: provider %q failed mid-stream: %w", e.Provider.Name(), err)
+	}
+
+	return fmt.Errorf("router: all providers failed: %w", lastErr)
+}
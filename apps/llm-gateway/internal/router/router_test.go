@@ -0,0 +1,121 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/vllm"
+)
+
+// fakeProvider is a no-op Provider used to build Entry values for testing
+// candidate ordering without any real upstream.
+type fakeProvider struct{ name string }
+
+func (f *fakeProvider) Name() string { return f.name }
+func (f *fakeProvider) Chat(ctx context.Context, req vllm.ChatCompletionRequest) (*vllm.ChatCompletionResponse, error) {
+	return nil, nil
+}
+func (f *fakeProvider) ChatStream(ctx context.Context, req vllm.ChatCompletionRequest, onChunk vllm.StreamHandler) error {
+	return nil
+}
+
+func entryNames(entries []Entry) []string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Provider.Name()
+	}
+	return names
+}
+
+func TestProviderWeight_NonPositiveDefaultsToOne(t *testing.T) {
+	if w := providerWeight(ProviderConfig{Weight: 0}); w != 1 {
+		t.Fatalf("providerWeight(0) = %d, want 1", w)
+	}
+	if w := providerWeight(ProviderConfig{Weight: -5}); w != 1 {
+		t.Fatalf("providerWeight(-5) = %d, want 1", w)
+	}
+	if w := providerWeight(ProviderConfig{Weight: 7}); w != 7 {
+		t.Fatalf("providerWeight(7) = %d, want 7", w)
+	}
+}
+
+func TestWeightedShuffle_IsAPermutation(t *testing.T) {
+	entries := []Entry{
+		{Provider: &fakeProvider{"a"}, Config: ProviderConfig{Name: "a", Weight: 1}},
+		{Provider: &fakeProvider{"b"}, Config: ProviderConfig{Name: "b", Weight: 10}},
+		{Provider: &fakeProvider{"c"}, Config: ProviderConfig{Name: "c", Weight: 0}},
+	}
+
+	shuffled := weightedShuffle(entries)
+	if len(shuffled) != len(entries) {
+		t.Fatalf("len(shuffled) = %d, want %d", len(shuffled), len(entries))
+	}
+
+	seen := map[string]bool{}
+	for _, e := range shuffled {
+		seen[e.Provider.Name()] = true
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if !seen[name] {
+			t.Fatalf("weightedShuffle dropped entry %q", name)
+		}
+	}
+}
+
+func TestWeightedShuffle_HeavierWeightWinsFirstSlotMostOften(t *testing.T) {
+	entries := []Entry{
+		{Provider: &fakeProvider{"light"}, Config: ProviderConfig{Name: "light", Weight: 1}},
+		{Provider: &fakeProvider{"heavy"}, Config: ProviderConfig{Name: "heavy", Weight: 99}},
+	}
+
+	heavyFirst := 0
+	const trials = 200
+	for i := 0; i < trials; i++ {
+		if weightedShuffle(entries)[0].Provider.Name() == "heavy" {
+			heavyFirst++
+		}
+	}
+
+	// With a 99:1 weight ratio, "heavy" should overwhelmingly land first;
+	// a generous threshold keeps this non-flaky while still catching a
+	// shuffle that ignores weight entirely (expected ~50%).
+	if heavyFirst < trials*80/100 {
+		t.Fatalf("heavy-weighted entry landed first in %d/%d trials, want a strong majority", heavyFirst, trials)
+	}
+}
+
+func TestWeightedPriorityOrder_PreservesPriorityTiers(t *testing.T) {
+	entries := []Entry{
+		{Provider: &fakeProvider{"p0-a"}, Config: ProviderConfig{Name: "p0-a", Priority: 0, Weight: 1}},
+		{Provider: &fakeProvider{"p0-b"}, Config: ProviderConfig{Name: "p0-b", Priority: 0, Weight: 1}},
+		{Provider: &fakeProvider{"p1-a"}, Config: ProviderConfig{Name: "p1-a", Priority: 1, Weight: 1}},
+	}
+
+	ordered := weightedPriorityOrder(entries)
+	names := entryNames(ordered)
+
+	if len(names) != 3 {
+		t.Fatalf("len(names) = %d, want 3", len(names))
+	}
+	if names[2] != "p1-a" {
+		t.Fatalf("priority-1 entry must always be tried last, got order %v", names)
+	}
+}
+
+func TestRouter_Candidates_HealthyBeforeUnhealthy(t *testing.T) {
+	entries := []Entry{
+		{Provider: &fakeProvider{"unhealthy"}, Config: ProviderConfig{Name: "unhealthy", Priority: 0, Weight: 1}},
+		{Provider: &fakeProvider{"healthy"}, Config: ProviderConfig{Name: "healthy", Priority: 1, Weight: 1}},
+	}
+
+	health := NewHealthTracker(1, time.Minute)
+	health.RecordFailure("unhealthy", true, false)
+
+	router := NewRouter(entries, health)
+	names := entryNames(router.candidates())
+
+	if names[0] != "healthy" {
+		t.Fatalf("candidates() = %v, want the healthy provider first despite its lower priority", names)
+	}
+}
@@ -0,0 +1,187 @@
+// Package sse implements a small reader for the Server-Sent Events wire
+// format (https://html.spec.whatwg.org/multipage/server-sent-events.html),
+// as used by OpenAI-compatible streaming APIs such as vLLM's.
+package sse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxEventSize bounds how many bytes of field data Reader will
+// accumulate for a single event before giving up, so a misbehaving or
+// malicious upstream can't exhaust memory by never sending a blank line.
+const DefaultMaxEventSize = 10 << 20 // 10MiB
+
+// ErrEventTooLarge is returned by Next when an event's accumulated field
+// data exceeds the Reader's configured max event size.
+var ErrEventTooLarge = errors.New("sse: event exceeds max event size")
+
+// Event is one dispatched SSE event, or a bare comment/keep-alive line.
+//
+// Comment is set (and every other field left zero) when the line read was
+// a ": ..." comment rather than a dispatched event; these carry no payload
+// but are how servers signal the connection is still alive, so callers
+// doing their own idle-timeout bookkeeping should treat receiving one the
+// same as receiving a real event.
+type Event struct {
+	ID      string
+	Event   string
+	Data    string
+	Retry   time.Duration
+	Comment string
+
+	// isComment distinguishes a bare ":" keep-alive (Comment == "") from the
+	// zero Event; Comment alone can't carry that distinction.
+	isComment bool
+}
+
+// IsComment reports whether this Event is a keep-alive comment rather than
+// a dispatched event.
+func (e Event) IsComment() bool { return e.isComment }
+
+// Reader parses an SSE byte stream into Events. Unlike bufio.Scanner, lines
+// (and accumulated event data) are not subject to a fixed token-size
+// ceiling other than the configured max event size, so a single large `data:` line - e.g. a
+// long tool-call JSON fragment - doesn't abort the stream.
+type Reader struct {
+	br           *bufio.Reader
+	maxEventSize int
+
+	lastID string // sticky "last event ID" buffer, per spec
+
+	// Accumulation state for an event still in progress, kept on the
+	// Reader (rather than local to Next) so that a comment/keep-alive
+	// returned mid-event doesn't discard data/event/id fields already
+	// buffered for it; the next Next call resumes accumulating instead of
+	// starting over.
+	data       strings.Builder
+	event      string
+	id         string
+	retry      time.Duration
+	dirty      bool // true once we've buffered at least one field line
+	inProgress bool
+}
+
+// NewReader returns a Reader with DefaultMaxEventSize.
+func NewReader(r io.Reader) *Reader {
+	return NewReaderSize(r, DefaultMaxEventSize)
+}
+
+// NewReaderSize returns a Reader that aborts an event once its accumulated
+// data exceeds maxEventSize bytes.
+func NewReaderSize(r io.Reader, maxEventSize int) *Reader {
+	return &Reader{
+		br:           bufio.NewReader(r),
+		maxEventSize: maxEventSize,
+	}
+}
+
+// Next reads and returns the next Event from the stream, blocking until a
+// full event is dispatched (a blank line following non-empty data), a
+// comment line is seen, or the underlying reader returns an error. Callers
+// should keep calling Next until it returns an error (io.EOF on a clean
+// close).
+//
+// A comment seen partway through an event's field lines is returned
+// immediately (so callers doing idle-timeout bookkeeping see it without
+// delay), but the fields already buffered for that event are preserved:
+// the next call to Next resumes accumulating them rather than discarding
+// them.
+func (r *Reader) Next() (Event, error) {
+	if !r.inProgress {
+		r.data.Reset()
+		r.event = ""
+		r.id = r.lastID
+		r.retry = 0
+		r.dirty = false
+		r.inProgress = true
+	}
+
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return Event{}, err
+		}
+
+		switch {
+		case line == "":
+			if !r.dirty || r.data.Len() == 0 {
+				// Per spec: a dispatch with an empty data buffer fires
+				// nothing. Reset and keep reading.
+				r.data.Reset()
+				r.event = ""
+				r.dirty = false
+				continue
+			}
+
+			r.lastID = r.id
+			// Dispatch joins multi-line data with "\n", but the field
+			// itself never ends in one.
+			text := strings.TrimSuffix(r.data.String(), "\n")
+			ev := Event{ID: r.id, Event: r.event, Data: text, Retry: r.retry}
+			r.inProgress = false
+			return ev, nil
+
+		case strings.HasPrefix(line, ":"):
+			return Event{Comment: strings.TrimPrefix(line, ":"), isComment: true}, nil
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			r.data.WriteString(value)
+			r.data.WriteByte('\n')
+			r.dirty = true
+			if r.maxEventSize > 0 && r.data.Len() > r.maxEventSize {
+				r.inProgress = false
+				return Event{}, ErrEventTooLarge
+			}
+		case "event":
+			r.event = value
+			r.dirty = true
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				r.id = value
+			}
+			r.dirty = true
+		case "retry":
+			if ms, convErr := strconv.Atoi(value); convErr == nil {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+			r.dirty = true
+		default:
+			// Unknown fields are ignored per spec.
+		}
+	}
+}
+
+// readLine returns the next line with its trailing "\r\n" or "\n" stripped.
+// Unlike bufio.Scanner it has no fixed maximum token size; bufio.Reader
+// grows its internal buffer as needed for arbitrarily long lines.
+func (r *Reader) readLine() (string, error) {
+	line, err := r.br.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			return strings.TrimSuffix(line, "\r"), nil
+		}
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return line, nil
+}
+
+func (e Event) String() string {
+	if e.IsComment() {
+		return fmt.Sprintf("sse.Event{comment=%q}", e.Comment)
+	}
+	return fmt.Sprintf("sse.Event{id=%q, event=%q, data=%q}", e.ID, e.Event, e.Data)
+}
@@ -0,0 +1,113 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReader_MultiLineData(t *testing.T) {
+	r := NewReader(strings.NewReader("data: hello\ndata: world\n\n"))
+
+	ev, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if ev.Data != "hello\nworld" {
+		t.Fatalf("Data = %q, want %q", ev.Data, "hello\nworld")
+	}
+}
+
+func TestReader_CommentMidEventPreservesBufferedFields(t *testing.T) {
+	r := NewReader(strings.NewReader("data: hello\n: keep-alive\ndata: world\n\n"))
+
+	comment, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next (comment): %v", err)
+	}
+	if !comment.IsComment() {
+		t.Fatalf("expected a comment event, got %+v", comment)
+	}
+
+	ev, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next (event): %v", err)
+	}
+	if ev.Data != "hello\nworld" {
+		t.Fatalf("Data = %q, want %q (the \"hello\" fragment must survive the mid-event comment)", ev.Data, "hello\nworld")
+	}
+}
+
+func TestReader_BareCommentIsComment(t *testing.T) {
+	r := NewReader(strings.NewReader(":\n\ndata: hello\n\n"))
+
+	comment, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next (comment): %v", err)
+	}
+	if !comment.IsComment() {
+		t.Fatalf("bare \":\" line must report IsComment() == true, got %+v", comment)
+	}
+	if comment.Comment != "" {
+		t.Fatalf("Comment = %q, want empty", comment.Comment)
+	}
+
+	ev, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next (event): %v", err)
+	}
+	if ev.Data != "hello" {
+		t.Fatalf("Data = %q, want %q", ev.Data, "hello")
+	}
+}
+
+func TestReader_IDIsStickyAcrossEvents(t *testing.T) {
+	r := NewReader(strings.NewReader("id: 1\ndata: first\n\ndata: second\n\n"))
+
+	first, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next (first): %v", err)
+	}
+	if first.ID != "1" {
+		t.Fatalf("first.ID = %q, want %q", first.ID, "1")
+	}
+
+	second, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next (second): %v", err)
+	}
+	if second.ID != "1" {
+		t.Fatalf("second.ID = %q, want sticky %q", second.ID, "1")
+	}
+}
+
+func TestReader_EmptyDispatchFiresNothing(t *testing.T) {
+	r := NewReader(strings.NewReader("\n\ndata: hello\n\n"))
+
+	ev, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if ev.Data != "hello" {
+		t.Fatalf("Data = %q, want %q (blank lines with no buffered data must not dispatch)", ev.Data, "hello")
+	}
+}
+
+func TestReader_MaxEventSizeExceeded(t *testing.T) {
+	r := NewReaderSize(strings.NewReader("data: 0123456789\n\n"), 4)
+
+	_, err := r.Next()
+	if !errors.Is(err, ErrEventTooLarge) {
+		t.Fatalf("err = %v, want ErrEventTooLarge", err)
+	}
+}
+
+func TestReader_EOFAfterCleanClose(t *testing.T) {
+	r := NewReader(strings.NewReader(""))
+
+	_, err := r.Next()
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("err = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,48 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink publishes each Event as a JSON message to a Kafka topic, keyed
+// by RequestID, for operators who already centralize billing/accounting
+// data through Kafka.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink returns a KafkaSink publishing to topic on the given
+// brokers.
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.LeastBytes{},
+		},
+	}
+}
+
+func (s *KafkaSink) Record(ctx context.Context, event Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("usage: marshal event: %w", err)
+	}
+
+	if err := s.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.RequestID),
+		Value: b,
+	}); err != nil {
+		return fmt.Errorf("usage: publish event: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the underlying Kafka writer.
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,59 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// PostgresSink inserts each Event as a row in a usage_events table, for
+// operators who want to join billing data against existing Postgres
+// reporting. An Event whose RequestID already has a row is silently not
+// inserted (ON CONFLICT DO NOTHING). The table is expected to already exist:
+//
+//	CREATE TABLE usage_events (
+//		request_id        text PRIMARY KEY,
+//		model             text NOT NULL,
+//		prompt_tokens     integer NOT NULL,
+//		completion_tokens integer NOT NULL,
+//		total_tokens      integer NOT NULL,
+//		finish_reason     text NOT NULL,
+//		duration_ms       bigint NOT NULL,
+//		ttft_ms           bigint NOT NULL,
+//		tool_calls        jsonb NOT NULL,
+//		error             text NOT NULL,
+//		recorded_at       timestamptz NOT NULL DEFAULT now()
+//	);
+type PostgresSink struct {
+	db *sql.DB
+}
+
+// NewPostgresSink wraps an existing *sql.DB. The caller owns the DB's
+// lifecycle (including closing it).
+func NewPostgresSink(db *sql.DB) *PostgresSink {
+	return &PostgresSink{db: db}
+}
+
+const insertUsageEvent = `
+INSERT INTO usage_events (
+	request_id, model, prompt_tokens, completion_tokens, total_tokens,
+	finish_reason, duration_ms, ttft_ms, tool_calls, error
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (request_id) DO NOTHING
+`
+
+func (s *PostgresSink) Record(ctx context.Context, event Event) error {
+	toolCalls, err := json.Marshal(event.ToolCalls)
+	if err != nil {
+		return fmt.Errorf("usage: marshal tool calls: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, insertUsageEvent,
+		event.RequestID, event.Model, event.PromptTokens, event.CompletionTokens, event.TotalTokens,
+		event.FinishReason, event.DurationMs, event.TTFTMs, toolCalls, event.Error,
+	); err != nil {
+		return fmt.Errorf("usage: insert event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// StdoutSink writes each Event as a JSON line to an io.Writer (os.Stdout by
+// default), for local development and for operators who just want to pipe
+// the audit trail into their existing log collector.
+type StdoutSink struct {
+	w io.Writer
+}
+
+// NewStdoutSink returns a StdoutSink writing to os.Stdout.
+func NewStdoutSink() *StdoutSink {
+	return &StdoutSink{w: os.Stdout}
+}
+
+func (s *StdoutSink) Record(_ context.Context, event Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("usage: marshal event: %w", err)
+	}
+	_, err = fmt.Fprintln(s.w, string(b))
+	return err
+}
@@ -0,0 +1,28 @@
+// Package usage defines the audit trail emitted each time a streamed chat
+// completion terminates, so billing/accounting has a single source of
+// truth instead of scraping it out of debug logs.
+package usage
+
+import "context"
+
+// Event is a single terminated ChatStream call, successful or not.
+type Event struct {
+	RequestID        string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	FinishReason     string
+	DurationMs       int64
+	TTFTMs           int64
+	ToolCalls        []string
+	Error            string
+}
+
+// Sink receives one Event per ChatStream call, whether it finished cleanly
+// or gave up with partial usage. Implementations should not block the
+// caller for long; slow sinks (Kafka, Postgres) should apply their own
+// timeout via ctx.
+type Sink interface {
+	Record(ctx context.Context, event Event) error
+}
@@ -1,25 +1,41 @@
 package vllm
 
 import (
-	"bufio"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/yungtweek/talkie/apps/llm-gateway/internal/logger"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/metrics"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/sse"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/usage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Client is a minimal HTTP client for talking to a vLLM server that exposes
 // an OpenAI-compatible /v1/chat/completions endpoint.
 type Client struct {
-	http *resty.Client
+	http      *resty.Client
+	usageSink usage.Sink
+}
+
+// toolCallAccumulator collects the streamed fragments for one tool call,
+// keyed by its index in the response.
+type toolCallAccumulator struct {
+	id        string
+	name      string
+	arguments strings.Builder
 }
 
 // OpenAIChatCompletionChunk represents a single SSE chunk for streamed chat completions.
@@ -37,8 +53,9 @@ type OpenAIChatCompletionChunk struct {
 		TokenIDs     []int  `json:"token_ids,omitempty"`
 
 		Delta struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
+			Role      string          `json:"role"`
+			Content   string          `json:"content"`
+			ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
 		} `json:"delta"`
 	} `json:"choices"`
 
@@ -68,46 +85,98 @@ func NewClient(baseURL string, timeoutMs int) *Client {
 	}
 }
 
+// SetUsageSink attaches a usage.Sink that receives a usage.Event each time
+// ChatStream terminates. The default (nil) disables usage auditing.
+func (c *Client) SetUsageSink(sink usage.Sink) {
+	c.usageSink = sink
+}
+
 // Chat sends a ChatCompletionRequest to the vLLM server and returns the parsed response.
-func (c *Client) Chat(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
-	logger.Log.Debug("vLLM Chat request",
+func (c *Client) Chat(ctx context.Context, req ChatCompletionRequest) (resp *ChatCompletionResponse, err error) {
+	ctx, span := metrics.Tracer.Start(ctx, "vllm.Chat", trace.WithAttributes(
+		attribute.String("model", req.Model),
+	))
+	defer span.End()
+
+	start := time.Now()
+	status := "success"
+	defer func() {
+		metrics.VLLMRequestsTotal.WithLabelValues(req.Model, status).Inc()
+		metrics.VLLMRequestDuration.WithLabelValues(req.Model, "false").Observe(time.Since(start).Seconds())
+	}()
+
+	log := logger.FromContext(ctx).With(zap.String("model", req.Model), zap.String("provider", "vllm"))
+
+	log.Debug("vLLM Chat request",
 		zap.String("endpoint", "/v1/chat/completions"),
 	)
 
-	var resp ChatCompletionResponse
+	var result ChatCompletionResponse
 
-	r, err := c.http.R().
+	r, reqErr := c.http.R().
 		SetContext(ctx).
 		SetBody(req).
-		SetResult(&resp).
+		SetResult(&result).
 		Post("/v1/chat/completions")
-	if err != nil {
-		logger.Log.Error("vLLM HTTP request failed",
-			zap.Error(err),
+	if reqErr != nil {
+		status = "error"
+		log.Error("vLLM HTTP request failed",
+			zap.Error(reqErr),
 		)
-		return nil, fmt.Errorf("vLLM HTTP request failed: %w", err)
+		span.RecordError(reqErr)
+		span.SetStatus(codes.Error, reqErr.Error())
+		return nil, fmt.Errorf("vLLM HTTP request failed: %w", reqErr)
 	}
 
-	logger.Log.Debug("vLLM HTTP response received",
+	log.Debug("vLLM HTTP response received",
 		zap.Int("status_code", r.StatusCode()),
 	)
 
-	if status := r.StatusCode(); status < 200 || status >= 300 {
-		logger.Log.Error("vLLM non-2xx status",
-			zap.Int("status_code", status),
+	if httpStatus := r.StatusCode(); httpStatus < 200 || httpStatus >= 300 {
+		status = "error"
+		log.Error("vLLM non-2xx status",
+			zap.Int("status_code", httpStatus),
 			zap.ByteString("body", r.Body()),
 		)
-		return nil, fmt.Errorf("vLLM returned non-2xx status %d: %s", status, string(r.Body()))
+		err = fmt.Errorf("vLLM returned non-2xx status %d: %s", httpStatus, string(r.Body()))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	}
 
-	return &resp, nil
+	var finishReason string
+	if len(result.Choices) > 0 {
+		finishReason = result.Choices[0].FinishReason
+	}
+	span.SetAttributes(
+		attribute.Int("prompt_tokens", result.Usage.PromptTokens),
+		attribute.String("finish_reason", finishReason),
+	)
+	metrics.VLLMTokensTotal.WithLabelValues(req.Model, "prompt").Add(float64(result.Usage.PromptTokens))
+	metrics.VLLMTokensTotal.WithLabelValues(req.Model, "completion").Add(float64(result.Usage.CompletionTokens))
+
+	return &result, nil
 }
 
-// ChatStream sends a streaming ChatCompletionRequest to the vLLM server.
-// It expects the vLLM server to expose an OpenAI-compatible SSE stream from
-// /v1/chat/completions when the request has Stream set to true.
-func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onChunk StreamHandler) error {
-	logger.Log.Debug("vLLM ChatStream request",
+// chatStreamOnce performs a single streaming attempt against vLLM, with no
+// reconnect logic of its own. It returns the number of completion tokens
+// produced during this attempt (even on error, so the caller can track how
+// much of max_tokens remains) alongside any error.
+func (c *Client) chatStreamOnce(ctx context.Context, req ChatCompletionRequest, onChunk StreamHandler, heartbeatTimeout time.Duration) (completionTokens int, err error) {
+	ctx, span := metrics.Tracer.Start(ctx, "vllm.ChatStream", trace.WithAttributes(
+		attribute.String("model", req.Model),
+	))
+	defer span.End()
+
+	start := time.Now()
+	sendTime := start
+	var ttftRecorded bool
+	var lastTokenTime time.Time
+	var sawToolCall bool
+
+	log := logger.FromContext(ctx).With(zap.String("model", req.Model), zap.String("provider", "vllm"))
+
+	log.Debug("vLLM ChatStream request",
 		zap.String("endpoint", "/v1/chat/completions"),
 	)
 
@@ -115,30 +184,71 @@ func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onCh
 	req.Stream = true
 	req.ReturnTokenIds = true
 
-	r, err := c.http.R().
+	r, reqErr := c.http.R().
 		SetContext(ctx).
 		SetBody(req).
 		SetDoNotParseResponse(true).
 		Post("/v1/chat/completions")
-	if err != nil {
-		logger.Log.Error("vLLM HTTP stream request failed", zap.Error(err))
-		return fmt.Errorf("vLLM HTTP stream request failed: %w", err)
+	if reqErr != nil {
+		log.Error("vLLM HTTP stream request failed", zap.Error(reqErr))
+		span.RecordError(reqErr)
+		span.SetStatus(codes.Error, reqErr.Error())
+		metrics.VLLMRequestsTotal.WithLabelValues(req.Model, "error").Inc()
+		metrics.VLLMRequestDuration.WithLabelValues(req.Model, "true").Observe(time.Since(start).Seconds())
+		return 0, &transportError{fmt.Errorf("vLLM HTTP stream request failed: %w", reqErr)}
 	}
 	defer func(body io.ReadCloser) {
-		err := body.Close()
-		if err != nil {
-			logger.Log.Warn("failed to close vLLM stream body", zap.Error(err))
+		if cerr := body.Close(); cerr != nil {
+			log.Warn("failed to close vLLM stream body", zap.Error(cerr))
 		}
 	}(r.RawBody())
 
 	if status := r.StatusCode(); status < 200 || status >= 300 {
-		logger.Log.Error("vLLM stream non-2xx status",
+		log.Error("vLLM stream non-2xx status",
 			zap.Int("status_code", status),
 		)
-		return fmt.Errorf("vLLM stream returned non-2xx status %d", status)
+		err := fmt.Errorf("vLLM stream returned non-2xx status %d", status)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		metrics.VLLMRequestsTotal.WithLabelValues(req.Model, "error").Inc()
+		metrics.VLLMRequestDuration.WithLabelValues(req.Model, "true").Observe(time.Since(start).Seconds())
+		return 0, err
 	}
 
-	scanner := bufio.NewScanner(r.RawBody())
+	reader := sse.NewReader(r.RawBody())
+
+	// frames decouples the blocking sse.Reader.Next() call from the
+	// heartbeat timer below: a goroutine pumps parsed frames into the
+	// channel so the select can also fire on an idle timeout even though
+	// Next() itself has no deadline awareness.
+	type frame struct {
+		event sse.Event
+		err   error
+	}
+	frames := make(chan frame, 1)
+	stopPump := make(chan struct{})
+	go func() {
+		for {
+			ev, ferr := reader.Next()
+			select {
+			case frames <- frame{ev, ferr}:
+			case <-stopPump:
+				return
+			}
+			if ferr != nil {
+				return
+			}
+		}
+	}()
+	defer close(stopPump)
+
+	var heartbeatC <-chan time.Time
+	var heartbeat *time.Timer
+	if heartbeatTimeout > 0 {
+		heartbeat = time.NewTimer(heartbeatTimeout)
+		defer heartbeat.Stop()
+		heartbeatC = heartbeat.C
+	}
 
 	var computedPromptTokens int
 	var computedCompletionTokens int
@@ -147,19 +257,109 @@ func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onCh
 	var lastIndex int
 	var sawDone bool
 
-	for scanner.Scan() {
-		rawLine := scanner.Text()
-		line := strings.TrimSpace(rawLine)
-		if line == "" {
-			continue
+	defer func() {
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		metrics.VLLMRequestsTotal.WithLabelValues(req.Model, status).Inc()
+		metrics.VLLMRequestDuration.WithLabelValues(req.Model, "true").Observe(time.Since(start).Seconds())
+		metrics.VLLMTokensTotal.WithLabelValues(req.Model, "prompt").Add(float64(computedPromptTokens))
+		metrics.VLLMTokensTotal.WithLabelValues(req.Model, "completion").Add(float64(computedCompletionTokens))
+
+		span.SetAttributes(
+			attribute.Int("prompt_tokens", computedPromptTokens),
+			attribute.String("finish_reason", lastFinishReason),
+			attribute.Bool("tool_call", sawToolCall),
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+
+	// toolCalls accumulates argument fragments per tool-call index, since
+	// OpenAI-compatible streams send function arguments as consecutive
+	// partial-JSON fragments rather than one shot.
+	toolCalls := make(map[int]*toolCallAccumulator)
+
+	flushToolCalls := func(index int, finishReason string) error {
+		indices := make([]int, 0, len(toolCalls))
+		for i := range toolCalls {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+
+		for _, i := range indices {
+			acc := toolCalls[i]
+			done := ChatCompletionStreamChunk{
+				Type:              "tool_call.done",
+				FinishReason:      finishReason,
+				Index:             index,
+				ToolCallIndex:     i,
+				ToolCallID:        acc.id,
+				ToolCallName:      acc.name,
+				ToolCallArguments: acc.arguments.String(),
+			}
+			if err := onChunk(done); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+streamLoop:
+	for {
+		var f frame
+		select {
+		case <-ctx.Done():
+			return computedCompletionTokens, ctx.Err()
+		case <-heartbeatC:
+			err := &transportError{fmt.Errorf("vLLM ChatStream heartbeat timeout: no SSE frame within %s", heartbeatTimeout)}
+			log.Warn("vLLM ChatStream terminating with partial usage",
+				zap.Int("prompt_tokens", computedPromptTokens),
+				zap.Int("completion_tokens", computedCompletionTokens),
+				zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
+				zap.String("finish_reason", lastFinishReason),
+				zap.Int("index", lastIndex),
+				zap.Bool("saw_done", sawDone),
+			)
+			return computedCompletionTokens, err
+		case f = <-frames:
+		}
+
+		if heartbeat != nil {
+			if !heartbeat.Stop() {
+				<-heartbeat.C
+			}
+			heartbeat.Reset(heartbeatTimeout)
+		}
+
+		if f.err != nil {
+			if f.err == io.EOF {
+				break streamLoop
+			}
+			log.Error("vLLM ChatStream SSE read error", zap.Error(f.err))
+			log.Warn("vLLM ChatStream terminating with partial usage",
+				zap.Int("prompt_tokens", computedPromptTokens),
+				zap.Int("completion_tokens", computedCompletionTokens),
+				zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
+				zap.String("finish_reason", lastFinishReason),
+				zap.Int("index", lastIndex),
+				zap.Bool("saw_done", sawDone),
+			)
+			return computedCompletionTokens, &transportError{fmt.Errorf("vLLM SSE read error: %w", f.err)}
 		}
 
-		if !strings.HasPrefix(line, "data: ") {
+		if f.event.IsComment() {
+			// Keep-alive; already reset the heartbeat above.
 			continue
 		}
 
-		payload := strings.TrimPrefix(line, "data: ")
-		logger.Log.Debug("vLLM stream chunk received", zap.String("payload", payload))
+		payload := f.event.Data
+		if ce := log.Check(zapcore.DebugLevel, "vLLM stream chunk received"); ce != nil {
+			ce.Write(zap.String("payload", payload))
+		}
 		if payload == "[DONE]" {
 			sawDone = true
 
@@ -181,8 +381,8 @@ func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onCh
 			}
 
 			if err := onChunk(finalChunk); err != nil {
-				logger.Log.Warn("ChatStream callback returned error (final chunk)", zap.Error(err))
-				logger.Log.Warn("vLLM ChatStream terminating with partial usage",
+				log.Warn("ChatStream callback returned error (final chunk)", zap.Error(err))
+				log.Warn("vLLM ChatStream terminating with partial usage",
 					zap.Int("prompt_tokens", computedPromptTokens),
 					zap.Int("completion_tokens", computedCompletionTokens),
 					zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
@@ -190,16 +390,16 @@ func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onCh
 					zap.Int("index", lastIndex),
 					zap.Bool("saw_done", sawDone),
 				)
-				return err
+				return computedCompletionTokens, err
 			}
 
-			break
+			break streamLoop
 		}
 
 		var raw OpenAIChatCompletionChunk
 		if err := json.Unmarshal([]byte(payload), &raw); err != nil {
-			logger.Log.Error("failed to unmarshal vLLM stream chunk", zap.Error(err))
-			logger.Log.Warn("vLLM ChatStream terminating with partial usage",
+			log.Error("failed to unmarshal vLLM stream chunk", zap.Error(err))
+			log.Warn("vLLM ChatStream terminating with partial usage",
 				zap.Int("prompt_tokens", computedPromptTokens),
 				zap.Int("completion_tokens", computedCompletionTokens),
 				zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
@@ -207,7 +407,7 @@ func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onCh
 				zap.Int("index", lastIndex),
 				zap.Bool("saw_done", sawDone),
 			)
-			return fmt.Errorf("failed to unmarshal vLLM stream chunk: %w", err)
+			return computedCompletionTokens, fmt.Errorf("failed to unmarshal vLLM stream chunk: %w", err)
 		}
 
 		if len(raw.Choices) == 0 {
@@ -232,55 +432,108 @@ func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onCh
 
 		// Prefer server-provided usage when present and non-zero; otherwise fall back to computed counts.
 		promptTokens := computedPromptTokens
-		completionTokens := computedCompletionTokens
+		deltaCompletionTokens := computedCompletionTokens
 		totalTokens := computedPromptTokens + computedCompletionTokens
 
 		if raw.Usage != nil && raw.Usage.TotalTokens > 0 {
 			promptTokens = raw.Usage.PromptTokens
-			completionTokens = raw.Usage.CompletionTokens
+			deltaCompletionTokens = raw.Usage.CompletionTokens
 			totalTokens = raw.Usage.TotalTokens
 		}
 
-		logger.Log.Debug("vLLM stream chunk content", zap.String("Content", choice.Delta.Content))
+		if ce := log.Check(zapcore.DebugLevel, "vLLM stream chunk content"); ce != nil {
+			ce.Write(zap.String("Content", choice.Delta.Content))
+		}
 
-		chunk := ChatCompletionStreamChunk{
-			Type:             "output_text.delta",
-			Text:             choice.Delta.Content,
-			FinishReason:     choice.FinishReason,
-			Index:            choice.Index,
-			PromptTokens:     promptTokens,
-			CompletionTokens: completionTokens,
-			TotalTokens:      totalTokens,
+		if len(choice.Delta.ToolCalls) > 0 {
+			sawToolCall = true
 		}
 
-		if err := onChunk(chunk); err != nil {
-			logger.Log.Warn("ChatStream callback returned error", zap.Error(err))
-			logger.Log.Warn("vLLM ChatStream terminating with partial usage",
-				zap.Int("prompt_tokens", computedPromptTokens),
-				zap.Int("completion_tokens", computedCompletionTokens),
-				zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
-				zap.String("finish_reason", lastFinishReason),
-				zap.Int("index", lastIndex),
-				zap.Bool("saw_done", sawDone),
-			)
-			return err
+		for _, tc := range choice.Delta.ToolCalls {
+			acc, ok := toolCalls[tc.Index]
+			if !ok {
+				acc = &toolCallAccumulator{}
+				toolCalls[tc.Index] = acc
+			}
+			if tc.ID != "" {
+				acc.id = tc.ID
+			}
+			if tc.Function.Name != "" {
+				acc.name = tc.Function.Name
+			}
+			acc.arguments.WriteString(tc.Function.Arguments)
+
+			delta := ChatCompletionStreamChunk{
+				Type:              "tool_call.delta",
+				Index:             choice.Index,
+				ToolCallIndex:     tc.Index,
+				ToolCallID:        acc.id,
+				ToolCallName:      acc.name,
+				ToolCallArguments: tc.Function.Arguments,
+			}
+			if err := onChunk(delta); err != nil {
+				log.Warn("ChatStream callback returned error (tool call delta)", zap.Error(err))
+				return computedCompletionTokens, err
+			}
+		}
+
+		if choice.Delta.Content != "" {
+			now := time.Now()
+			if !ttftRecorded {
+				metrics.VLLMStreamTTFT.WithLabelValues(req.Model).Observe(now.Sub(sendTime).Seconds())
+				ttftRecorded = true
+			} else {
+				metrics.VLLMStreamInterTokenLatency.WithLabelValues(req.Model).Observe(now.Sub(lastTokenTime).Seconds())
+			}
+			lastTokenTime = now
+		}
+
+		if choice.Delta.Content != "" || len(choice.Delta.ToolCalls) == 0 {
+			chunk := ChatCompletionStreamChunk{
+				Type:             "output_text.delta",
+				Text:             choice.Delta.Content,
+				FinishReason:     choice.FinishReason,
+				Index:            choice.Index,
+				PromptTokens:     promptTokens,
+				CompletionTokens: deltaCompletionTokens,
+				TotalTokens:      totalTokens,
+			}
+
+			if err := onChunk(chunk); err != nil {
+				log.Warn("ChatStream callback returned error", zap.Error(err))
+				log.Warn("vLLM ChatStream terminating with partial usage",
+					zap.Int("prompt_tokens", computedPromptTokens),
+					zap.Int("completion_tokens", computedCompletionTokens),
+					zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
+					zap.String("finish_reason", lastFinishReason),
+					zap.Int("index", lastIndex),
+					zap.Bool("saw_done", sawDone),
+				)
+				return computedCompletionTokens, err
+			}
+		}
+
+		if choice.FinishReason == "tool_calls" {
+			if err := flushToolCalls(choice.Index, choice.FinishReason); err != nil {
+				log.Warn("ChatStream callback returned error (tool call done)", zap.Error(err))
+				return computedCompletionTokens, err
+			}
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		logger.Log.Error("vLLM ChatStream scanner error", zap.Error(err))
-		logger.Log.Warn("vLLM ChatStream terminating with partial usage",
+	if !sawDone {
+		// The body closed cleanly but without a terminal [DONE] marker, so
+		// the generation is incomplete rather than finished.
+		log.Warn("vLLM ChatStream body closed before [DONE]",
 			zap.Int("prompt_tokens", computedPromptTokens),
 			zap.Int("completion_tokens", computedCompletionTokens),
-			zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
 			zap.String("finish_reason", lastFinishReason),
 			zap.Int("index", lastIndex),
-			zap.Bool("saw_done", sawDone),
 		)
-		return fmt.Errorf("vLLM stream scanner error: %w", err)
+		return computedCompletionTokens, &transportError{fmt.Errorf("vLLM stream closed before [DONE]")}
 	}
 
-	logger.Log.Info("vLLM ChatStream finished",
+	log.Info("vLLM ChatStream finished",
 		zap.Int("prompt_tokens", computedPromptTokens),
 		zap.Int("completion_tokens", computedCompletionTokens),
 		zap.Int("total_tokens", computedPromptTokens+computedCompletionTokens),
@@ -288,6 +541,6 @@ func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onCh
 		zap.Int("index", lastIndex),
 		zap.Bool("saw_done", sawDone),
 	)
-	logger.Log.Debug("vLLM ChatStream completed")
-	return nil
+	log.Debug("vLLM ChatStream completed")
+	return computedCompletionTokens, nil
 }
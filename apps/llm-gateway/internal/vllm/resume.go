@@ -0,0 +1,245 @@
+package vllm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/logger"
+	"github.com/yungtweek/talkie/apps/llm-gateway/internal/usage"
+	"go.uber.org/zap"
+)
+
+// transportError marks a chatStreamOnce failure as a connection-level
+// problem (request never landed, stream closed early, SSE read errored,
+// heartbeat timed out)
+// rather than an API rejection or a caller-initiated abort. Only transport
+// errors are eligible for reconnection.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// ChatStreamOptions configures ChatStream's mid-stream resumption. The zero
+// value disables reconnection entirely (MaxReconnects defaults to 0).
+type ChatStreamOptions struct {
+	// MaxReconnects is how many times ChatStream will reissue the request
+	// after a transport error before giving up.
+	MaxReconnects int
+
+	// ReconnectBackoff is how long to wait before each reconnect attempt.
+	ReconnectBackoff time.Duration
+
+	// OnReconnect, if set, is called before each reconnect attempt with the
+	// 1-based attempt number and the error that triggered it.
+	OnReconnect func(attempt int, reason error)
+
+	// HeartbeatTimeout, if non-zero, bounds how long ChatStream will wait
+	// for the next SSE frame (event or comment/keep-alive) before treating
+	// the connection as stuck. Firing counts as a transport error, so it is
+	// itself eligible for reconnection like any other dropped connection.
+	HeartbeatTimeout time.Duration
+}
+
+// ChatStream sends a streaming ChatCompletionRequest to the vLLM server. On
+// a transport-level failure (dropped connection, SSE read error, body closed
+// before [DONE]) it reissues the request with the partial assistant output
+// appended as context and a reduced max_tokens, relying on vLLM's automatic
+// prefix caching to make the retry cheap. Text vLLM re-emits from before the
+// disconnect is suppressed rather than forwarded a second time, as long as
+// it matches what was already forwarded; once a reissued stream diverges
+// from that text, everything it has emitted in that attempt is forwarded,
+// including any leading part that repeats earlier output. If a usage.Sink is attached via
+// SetUsageSink, it receives one usage.Event when ChatStream terminates,
+// whether it finished cleanly or gave up with partial usage.
+func (c *Client) ChatStream(ctx context.Context, req ChatCompletionRequest, onChunk StreamHandler, opts ...ChatStreamOptions) (err error) {
+	var o ChatStreamOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	log := logger.FromContext(ctx).With(zap.String("model", req.Model), zap.String("provider", "vllm"))
+
+	start := time.Now()
+	var usagePromptTokens, usageCompletionTokens, usageTotalTokens int
+	var usageFinishReason string
+	var usageFromDone bool // true once an output_text.done chunk has populated the fields above
+	var lastPromptTokens int
+	var lastFinishReason string
+	var toolCallNames []string
+	var firstTokenSeen bool
+	var ttft time.Duration
+
+	if c.usageSink != nil {
+		defer func() {
+			event := usage.Event{
+				RequestID:        logger.RequestID(ctx),
+				Model:            req.Model,
+				PromptTokens:     usagePromptTokens,
+				CompletionTokens: usageCompletionTokens,
+				TotalTokens:      usageTotalTokens,
+				FinishReason:     usageFinishReason,
+				DurationMs:       time.Since(start).Milliseconds(),
+				TTFTMs:           ttft.Milliseconds(),
+				ToolCalls:        toolCallNames,
+			}
+			if err != nil {
+				event.Error = err.Error()
+			}
+			if sinkErr := c.usageSink.Record(ctx, event); sinkErr != nil {
+				log.Warn("failed to record vLLM ChatStream usage event", zap.Error(sinkErr))
+			}
+		}()
+	}
+
+	var accumulated strings.Builder
+	var priorCompletionTokens int
+	attemptReq := req
+
+	// recordPartialUsage fills the usage fields from the last partial state
+	// seen so far, for error paths that never reach an output_text.done
+	// chunk (heartbeat timeout, SSE read error, unmarshal failure, body
+	// closed before [DONE], or reconnects exhausted).
+	recordPartialUsage := func() {
+		if usageFromDone {
+			return
+		}
+		usagePromptTokens = lastPromptTokens
+		usageCompletionTokens = priorCompletionTokens
+		usageTotalTokens = lastPromptTokens + priorCompletionTokens
+		usageFinishReason = lastFinishReason
+	}
+
+	for attempt := 0; ; attempt++ {
+		suppressor := newPrefixSuppressor(accumulated.String())
+
+		wrapped := func(chunk ChatCompletionStreamChunk) error {
+			if chunk.Type == "output_text.delta" && chunk.Text != "" {
+				suffix, forward := suppressor.filter(chunk.Text)
+				if !forward {
+					return nil
+				}
+				chunk.Text = suffix
+				accumulated.WriteString(suffix)
+			}
+			if chunk.Type == "output_text.delta" || chunk.Type == "output_text.done" {
+				chunk.CompletionTokens += priorCompletionTokens
+				chunk.TotalTokens += priorCompletionTokens
+			}
+
+			if chunk.Type == "output_text.delta" && chunk.Text != "" && !firstTokenSeen {
+				ttft = time.Since(start)
+				firstTokenSeen = true
+			}
+			if chunk.Type == "output_text.delta" {
+				lastPromptTokens = chunk.PromptTokens
+				if chunk.FinishReason != "" {
+					lastFinishReason = chunk.FinishReason
+				}
+			}
+			if chunk.Type == "output_text.done" {
+				usagePromptTokens = chunk.PromptTokens
+				usageCompletionTokens = chunk.CompletionTokens
+				usageTotalTokens = chunk.TotalTokens
+				usageFinishReason = chunk.FinishReason
+				usageFromDone = true
+			}
+			if chunk.Type == "tool_call.done" {
+				toolCallNames = append(toolCallNames, chunk.ToolCallName)
+			}
+
+			return onChunk(chunk)
+		}
+
+		tokensThisAttempt, attemptErr := c.chatStreamOnce(ctx, attemptReq, wrapped, o.HeartbeatTimeout)
+		priorCompletionTokens += tokensThisAttempt
+
+		if attemptErr == nil {
+			return nil
+		}
+
+		var te *transportError
+		if !errors.As(attemptErr, &te) || attempt >= o.MaxReconnects {
+			recordPartialUsage()
+			return attemptErr
+		}
+
+		log.Warn("vLLM ChatStream reconnecting after transport error",
+			zap.Int("attempt", attempt+1),
+			zap.Error(attemptErr),
+		)
+		if o.OnReconnect != nil {
+			o.OnReconnect(attempt+1, attemptErr)
+		}
+
+		if o.ReconnectBackoff > 0 {
+			select {
+			case <-time.After(o.ReconnectBackoff):
+			case <-ctx.Done():
+				recordPartialUsage()
+				return ctx.Err()
+			}
+		}
+
+		attemptReq = req
+		attemptReq.Messages = append(append([]ChatMessage{}, req.Messages...), ChatMessage{
+			Role:    RoleAssistant,
+			Content: accumulated.String(),
+		})
+		if req.MaxTokens > 0 {
+			remaining := req.MaxTokens - priorCompletionTokens
+			if remaining <= 0 {
+				recordPartialUsage()
+				return &transportError{attemptErr}
+			}
+			attemptReq.MaxTokens = remaining
+		}
+	}
+}
+
+// prefixSuppressor drops text that a reconnected stream re-emits from
+// before the disconnect, forwarding only the first unseen suffix.
+type prefixSuppressor struct {
+	already string
+	seen    strings.Builder
+	done    bool // true once we've stopped suppressing for this attempt
+}
+
+func newPrefixSuppressor(already string) *prefixSuppressor {
+	return &prefixSuppressor{already: already, done: already == ""}
+}
+
+// filter returns the portion of text that hasn't already been forwarded to
+// the caller, and whether anything should be forwarded at all.
+func (s *prefixSuppressor) filter(text string) (string, bool) {
+	if s.done {
+		return text, true
+	}
+
+	s.seen.WriteString(text)
+	seen := s.seen.String()
+
+	if len(seen) <= len(s.already) {
+		if strings.HasPrefix(s.already, seen) {
+			// Still entirely inside the already-forwarded prefix; drop it.
+			return "", false
+		}
+		// Diverged from the expected prefix; stop suppressing and forward
+		// everything accumulated so far.
+		s.done = true
+		return seen, true
+	}
+
+	if strings.HasPrefix(seen, s.already) {
+		s.done = true
+		return seen[len(s.already):], true
+	}
+
+	// Longer than the prefix but doesn't match it: diverged partway
+	// through. Forward what we have rather than lose it.
+	s.done = true
+	return seen, true
+}
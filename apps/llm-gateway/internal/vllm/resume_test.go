@@ -0,0 +1,54 @@
+package vllm
+
+import "testing"
+
+func TestPrefixSuppressor_SuppressesKnownPrefixThenForwardsRest(t *testing.T) {
+	s := newPrefixSuppressor("hello world")
+
+	if text, forward := s.filter("hello "); forward {
+		t.Fatalf("filter(%q) = (%q, true), want suppressed", "hello ", text)
+	}
+	if text, forward := s.filter("world"); forward {
+		t.Fatalf("filter(%q) = (%q, true), want still suppressed", "world", text)
+	}
+	text, forward := s.filter(", friend")
+	if !forward {
+		t.Fatalf("filter(%q) did not forward once the prefix was fully consumed", ", friend")
+	}
+	if text != ", friend" {
+		t.Fatalf("text = %q, want %q", text, ", friend")
+	}
+}
+
+func TestPrefixSuppressor_DivergesPartwayThroughPrefix(t *testing.T) {
+	s := newPrefixSuppressor("hello world")
+
+	text, forward := s.filter("hello there")
+	if !forward {
+		t.Fatalf("filter(%q) should forward once the stream diverges from the expected prefix", "hello there")
+	}
+	if text != "hello there" {
+		t.Fatalf("text = %q, want the full accumulated divergent text %q", text, "hello there")
+	}
+}
+
+func TestPrefixSuppressor_LongerThanPrefixButDiverges(t *testing.T) {
+	s := newPrefixSuppressor("hi")
+
+	text, forward := s.filter("hey")
+	if !forward {
+		t.Fatalf("filter(%q) should forward rather than lose the divergent text", "hey")
+	}
+	if text != "hey" {
+		t.Fatalf("text = %q, want %q", text, "hey")
+	}
+}
+
+func TestPrefixSuppressor_EmptyPrefixNeverSuppresses(t *testing.T) {
+	s := newPrefixSuppressor("")
+
+	text, forward := s.filter("anything")
+	if !forward || text != "anything" {
+		t.Fatalf("filter(%q) = (%q, %v), want (%q, true)", "anything", text, forward, "anything")
+	}
+}
@@ -0,0 +1,144 @@
+package vllm
+
+import "encoding/json"
+
+// Chat message roles. RoleTool marks a message carrying the result of a
+// tool call back to the model, referencing the originating call by
+// ToolCallID.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// ChatMessage is a single turn in a chat completion request, following the
+// OpenAI-compatible schema vLLM exposes on /v1/chat/completions.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content,omitempty"`
+
+	// ToolCalls is populated on assistant messages that invoke one or more
+	// tools.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+
+	// ToolCallID identifies the ToolCall this message is a result for. Only
+	// set on messages with Role == RoleTool.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+// NewToolMessage builds a RoleTool message carrying the result of a
+// previously requested tool call, for appending to Messages before the
+// next Chat/ChatStream call.
+func NewToolMessage(toolCallID, content string) ChatMessage {
+	return ChatMessage{Role: RoleTool, Content: content, ToolCallID: toolCallID}
+}
+
+// ToolFunction describes a callable function in a Tool definition.
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Parameters is a JSON Schema object describing the function's
+	// arguments, passed through to the model as-is.
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// Tool is a single entry in ChatCompletionRequest.Tools. Only the
+// "function" tool type is currently supported, matching vLLM's
+// OpenAI-compatible surface.
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolCall is a fully-formed function call, either returned by the model
+// (ChatMessage.ToolCalls) or echoed back with its result (ChatMessage.ToolCallID).
+type ToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// ToolCallDelta is a fragment of a tool call as streamed in a chat
+// completion chunk's delta. vLLM (like OpenAI) streams function arguments
+// as consecutive JSON string fragments that must be concatenated by index;
+// ID and Function.Name are only present on the first fragment for a given
+// index.
+type ToolCallDelta struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function,omitempty"`
+}
+
+// ChatCompletionRequest is the OpenAI-compatible payload sent to vLLM.
+type ChatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	Stream      bool          `json:"stream,omitempty"`
+	Temperature float64       `json:"temperature,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+
+	// Tools lists the functions the model may call. ToolChoice is either
+	// the string "auto"/"none"/"required" or an object pinning a specific
+	// tool, per the OpenAI tool-calling schema.
+	Tools      []Tool `json:"tools,omitempty"`
+	ToolChoice any    `json:"tool_choice,omitempty"`
+
+	// ReturnTokenIds asks vLLM to include prompt_token_ids/token_ids on the
+	// response so the gateway can compute usage without its own tokenizer.
+	ReturnTokenIds bool `json:"return_token_ids,omitempty"`
+}
+
+// ChatCompletionResponse is the OpenAI-compatible non-streaming response.
+type ChatCompletionResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+
+	Choices []struct {
+		Index        int         `json:"index"`
+		Message      ChatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// ChatCompletionStreamChunk is the normalized chunk ChatStream emits to its
+// caller, decoupled from whatever wire format the upstream provider uses.
+//
+// Type is one of "output_text.delta", "output_text.done", "tool_call.delta",
+// or "tool_call.done". The tool-call variants populate the ToolCall* fields
+// instead of Text; ToolCallArguments carries just the new fragment on
+// ".delta" and the full concatenated JSON arguments on ".done".
+type ChatCompletionStreamChunk struct {
+	Type             string `json:"type"`
+	Text             string `json:"text"`
+	FinishReason     string `json:"finish_reason,omitempty"`
+	Index            int    `json:"index"`
+	PromptTokens     int    `json:"prompt_tokens,omitempty"`
+	CompletionTokens int    `json:"completion_tokens,omitempty"`
+	TotalTokens      int    `json:"total_tokens,omitempty"`
+
+	ToolCallIndex     int    `json:"tool_call_index,omitempty"`
+	ToolCallID        string `json:"tool_call_id,omitempty"`
+	ToolCallName      string `json:"tool_call_name,omitempty"`
+	ToolCallArguments string `json:"tool_call_arguments,omitempty"`
+}
+
+// StreamHandler is invoked once per normalized stream chunk. Returning an
+// error aborts the stream.
+type StreamHandler func(ChatCompletionStreamChunk) error